@@ -0,0 +1,103 @@
+package obinary
+
+import (
+	"context"
+	"time"
+)
+
+//
+// withDeadline runs fn with dbc.conx's read/write deadlines derived from
+// ctx, so long-running socket I/O unblocks the instant ctx is canceled or
+// its deadline passes instead of waiting on the OS-level socket timeout.
+// A background goroutine watches ctx.Done() and forces the deadline with
+// SetDeadline(time.Now()) to interrupt any I/O already in flight.
+//
+// The deadline is always reset to none before returning, even when ctx
+// never had one, and the watcher goroutine is joined first - otherwise a
+// watcher that fires after withDeadline has already returned could call
+// SetDeadline(time.Now()) on dbc.conx behind the next unrelated call's
+// back, wedging it with an already-expired deadline.
+//
+func withDeadline(ctx context.Context, dbc *DbClient, fn func() error) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := dbc.conx.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	stopWatching := make(chan struct{})
+	watcherDone := make(chan struct{})
+
+	go func() {
+		defer close(watcherDone)
+		select {
+		case <-ctx.Done():
+			dbc.conx.SetDeadline(time.Now())
+		case <-stopWatching:
+		}
+	}()
+
+	err := fn()
+
+	close(stopWatching)
+	<-watcherDone
+	if resetErr := dbc.conx.SetDeadline(time.Time{}); err == nil {
+		err = resetErr
+	}
+
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+//
+// CreateServerSessionContext is CreateServerSession with a context.Context
+// that bounds how long the login may run, for callers such as gRPC/HTTP
+// handlers that need to enforce a per-request timeout.
+//
+func CreateServerSessionContext(ctx context.Context, dbc *DbClient, adminUser, adminPassw string) error {
+	return withDeadline(ctx, dbc, func() error {
+		return CreateServerSession(dbc, adminUser, adminPassw)
+	})
+}
+
+// CreateDatabaseContext is CreateDatabase with a context.Context that
+// bounds how long the REQUEST_DB_CREATE round-trip may run.
+func CreateDatabaseContext(ctx context.Context, dbc *DbClient, dbname, dbtype, storageType string) error {
+	return withDeadline(ctx, dbc, func() error {
+		return CreateDatabase(dbc, dbname, dbtype, storageType)
+	})
+}
+
+// DropDatabaseContext is DropDatabase with a context.Context that bounds
+// how long the REQUEST_DB_DROP round-trip may run.
+func DropDatabaseContext(ctx context.Context, dbc *DbClient, dbname, dbtype string) error {
+	return withDeadline(ctx, dbc, func() error {
+		return DropDatabase(dbc, dbname, dbtype)
+	})
+}
+
+// DatabaseExistsContext is DatabaseExists with a context.Context that
+// bounds how long the REQUEST_DB_EXIST round-trip may run.
+func DatabaseExistsContext(ctx context.Context, dbc *DbClient, dbname, storageType string) (bool, error) {
+	var exists bool
+	err := withDeadline(ctx, dbc, func() error {
+		var err error
+		exists, err = DatabaseExists(dbc, dbname, storageType)
+		return err
+	})
+	return exists, err
+}
+
+// ListDatabasesContext is ListDatabases with a context.Context that
+// bounds how long the REQUEST_DB_LIST round-trip may run.
+func ListDatabasesContext(ctx context.Context, dbc *DbClient) (map[string]DatabaseInfo, error) {
+	var dbs map[string]DatabaseInfo
+	err := withDeadline(ctx, dbc, func() error {
+		var err error
+		dbs, err = ListDatabases(dbc)
+		return err
+	})
+	return dbs, err
+}
@@ -0,0 +1,276 @@
+package obinary
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//
+// PoolConfig bounds how a Pool manages its underlying DbClient
+// connections, mirroring the knobs database/sql exposes for *sql.DB.
+//
+type PoolConfig struct {
+	MaxOpen     int           // max connections open per key; 0 means unbounded
+	MaxIdle     int           // max idle connections kept per key
+	MaxLifetime time.Duration // max age of a connection before it is retired; 0 means no limit
+	IdleTimeout time.Duration // max time a connection may sit idle before it is retired; 0 means no limit
+}
+
+// poolKey identifies one set of interchangeable connections: the same
+// server, database, user, and storage type.
+type poolKey struct {
+	host        string
+	dbname      string
+	user        string
+	storageType string
+}
+
+type pooledConn struct {
+	dbc       *DbClient
+	key       poolKey
+	createdAt time.Time
+	idleSince time.Time
+}
+
+//
+// Pool manages a bounded set of *DbClient connections keyed by
+// (host, dbname, user, storageType), so that applications - e.g. concurrent
+// Go HTTP handlers - don't pay for a fresh TCP socket and OrientDB login on
+// every request. An idle connection is checked with a DatabaseExists
+// heartbeat before reuse and evicted whenever that heartbeat returns an
+// error. The heartbeat goes through sendRequest, which reconnects in place
+// only when the request write itself fails with io.EOF or a broken pipe and
+// the client holds a token-based session (ClientOptions.UseTokenAuth); any
+// other failure, including one while reading the response, evicts the
+// connection.
+//
+type Pool struct {
+	cfg PoolConfig
+
+	mu      sync.Mutex
+	idle    map[poolKey][]*pooledConn
+	numOpen map[poolKey]int
+	waiters map[poolKey][]chan struct{}
+	inUse   map[*DbClient]*pooledConn
+}
+
+// NewPool creates a Pool governed by cfg. A zero-value PoolConfig means
+// unbounded connections with no idle/lifetime limits.
+func NewPool(cfg PoolConfig) *Pool {
+	return &Pool{
+		cfg:     cfg,
+		idle:    make(map[poolKey][]*pooledConn),
+		numOpen: make(map[poolKey]int),
+		waiters: make(map[poolKey][]chan struct{}),
+		inUse:   make(map[*DbClient]*pooledConn),
+	}
+}
+
+//
+// Acquire returns a *DbClient logged into dbname on host as user. storageType
+// must be PersistentStorageType or VolatileStorageType and is used both to
+// key the pool (so a volatile and a persistent database of the same name
+// don't share connections) and for the idle-connection heartbeat. It reuses
+// an idle connection that passes a DatabaseExists heartbeat, opens a new one
+// if the pool has room, or blocks until ctx is done or a connection already
+// in use is Released.
+//
+func (p *Pool) Acquire(ctx context.Context, host, dbname, user, passw, storageType string) (*DbClient, error) {
+	key := poolKey{host: host, dbname: dbname, user: user, storageType: storageType}
+
+	for {
+		p.mu.Lock()
+		if pc := p.takeIdleLocked(key); pc != nil {
+			p.mu.Unlock()
+
+			if _, err := DatabaseExists(pc.dbc, dbname, storageType); err != nil {
+				p.closeAndForget(key, pc)
+				continue
+			}
+
+			p.mu.Lock()
+			p.inUse[pc.dbc] = pc
+			p.mu.Unlock()
+			return pc.dbc, nil
+		}
+
+		if p.cfg.MaxOpen > 0 && p.numOpen[key] >= p.cfg.MaxOpen {
+			wait := make(chan struct{})
+			p.waiters[key] = append(p.waiters[key], wait)
+			p.mu.Unlock()
+
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				p.mu.Lock()
+				p.removeWaiterLocked(key, wait)
+				p.mu.Unlock()
+				return nil, ctx.Err()
+			}
+		}
+
+		p.numOpen[key]++
+		p.mu.Unlock()
+
+		dbc, err := dialPooled(ctx, host, dbname, user, passw)
+		if err != nil {
+			p.mu.Lock()
+			p.numOpen[key]--
+			p.mu.Unlock()
+			return nil, err
+		}
+
+		pc := &pooledConn{dbc: dbc, key: key, createdAt: time.Now()}
+		p.mu.Lock()
+		p.inUse[dbc] = pc
+		p.mu.Unlock()
+		return dbc, nil
+	}
+}
+
+//
+// Release returns dbc to the pool for reuse, or closes it outright if the
+// pool already holds MaxIdle idle connections for its key. Releasing a
+// *DbClient the pool did not hand out is a no-op.
+//
+func (p *Pool) Release(dbc *DbClient) {
+	p.mu.Lock()
+	pc, ok := p.inUse[dbc]
+	if !ok {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.inUse, dbc)
+
+	if p.cfg.MaxIdle > 0 && len(p.idle[pc.key]) >= p.cfg.MaxIdle {
+		p.numOpen[pc.key]--
+		p.wakeWaiterLocked(pc.key)
+		p.mu.Unlock()
+		dbc.conx.Close()
+		return
+	}
+
+	pc.idleSince = time.Now()
+	p.idle[pc.key] = append(p.idle[pc.key], pc)
+	p.wakeWaiterLocked(pc.key)
+	p.mu.Unlock()
+}
+
+//
+// takeIdleLocked pops the most recently idled, still-fresh connection for
+// key, retiring any it finds that have outlived MaxLifetime/IdleTimeout
+// along the way. p.mu must be held.
+//
+func (p *Pool) takeIdleLocked(key poolKey) *pooledConn {
+	conns := p.idle[key]
+	for len(conns) > 0 {
+		pc := conns[len(conns)-1]
+		conns = conns[:len(conns)-1]
+		p.idle[key] = conns
+
+		if p.expired(pc) {
+			p.numOpen[key]--
+			pc.dbc.conx.Close()
+			continue
+		}
+		return pc
+	}
+	return nil
+}
+
+func (p *Pool) expired(pc *pooledConn) bool {
+	now := time.Now()
+	if p.cfg.MaxLifetime > 0 && now.Sub(pc.createdAt) > p.cfg.MaxLifetime {
+		return true
+	}
+	if p.cfg.IdleTimeout > 0 && now.Sub(pc.idleSince) > p.cfg.IdleTimeout {
+		return true
+	}
+	return false
+}
+
+// closeAndForget closes a connection that failed its heartbeat and frees
+// its slot so a new connection can take its place.
+func (p *Pool) closeAndForget(key poolKey, pc *pooledConn) {
+	pc.dbc.conx.Close()
+
+	p.mu.Lock()
+	p.numOpen[key]--
+	p.wakeWaiterLocked(key)
+	p.mu.Unlock()
+}
+
+// wakeWaiterLocked signals the oldest Acquire call blocked on key, if any,
+// that a slot may now be available. p.mu must be held.
+func (p *Pool) wakeWaiterLocked(key poolKey) {
+	waiters := p.waiters[key]
+	if len(waiters) == 0 {
+		return
+	}
+	close(waiters[0])
+	p.waiters[key] = waiters[1:]
+}
+
+// removeWaiterLocked removes wait from key's waiter queue when its Acquire
+// call is abandoning it via ctx.Done(), so a canceled waiter left at the
+// head of the queue can't silently swallow the next freed slot's wakeup.
+// If wait is no longer in the queue, wakeWaiterLocked already popped and
+// closed it for this waiter, who is bailing out without consuming it; in
+// that case the wakeup is passed along to whoever is next in line instead
+// of being lost. p.mu must be held.
+func (p *Pool) removeWaiterLocked(key poolKey, wait chan struct{}) {
+	waiters := p.waiters[key]
+	for i, w := range waiters {
+		if w == wait {
+			p.waiters[key] = append(waiters[:i], waiters[i+1:]...)
+			return
+		}
+	}
+	p.wakeWaiterLocked(key)
+}
+
+//
+// dialPooled opens and authenticates a fresh connection the same way
+// OpenDatabase does, honoring ctx cancellation while the login is in
+// flight, so a pooled *DbClient is indistinguishable from one the caller
+// opened by hand. If ctx is done before OpenDatabase returns, the
+// connection is closed as soon as it completes instead of being leaked -
+// the caller already gave up on it, so it can be neither returned nor
+// pooled.
+//
+// Note: OpenDatabase itself does not yet negotiate stateless token-based
+// sessions or transparently reconnect the way CreateServerSession does;
+// database-level connections handed out by this pool only get that
+// behavior once OpenDatabase is updated to match.
+//
+func dialPooled(ctx context.Context, host, dbname, user, passw string) (*DbClient, error) {
+	type result struct {
+		dbc *DbClient
+		err error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		dbc, err := openDatabaseFn(host, dbname, user, passw)
+		done <- result{dbc, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.dbc, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-done; r.err == nil {
+				r.dbc.conx.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// openDatabaseFn is the hook dialPooled uses to open and authenticate a
+// fresh connection. It defaults to OpenDatabase and is a variable purely
+// so tests can substitute a fake dialer without a live OrientDB server.
+var openDatabaseFn = OpenDatabase
@@ -0,0 +1,129 @@
+package obinary
+
+import (
+	"testing"
+	"time"
+)
+
+// Pool's bookkeeping helpers below don't need a live *DbClient to exercise:
+// expired and wakeWaiterLocked only ever touch pooledConn/poolKey/channel
+// state. Acquire/Release/dialPooled themselves can't be driven from a test
+// in this tree, since it has no DbClient definition to construct one from.
+
+func TestPoolExpired(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name string
+		cfg  PoolConfig
+		pc   *pooledConn
+		want bool
+	}{
+		{
+			name: "fresh connection",
+			cfg:  PoolConfig{MaxLifetime: time.Hour, IdleTimeout: time.Minute},
+			pc:   &pooledConn{createdAt: now, idleSince: now},
+			want: false,
+		},
+		{
+			name: "exceeded MaxLifetime",
+			cfg:  PoolConfig{MaxLifetime: time.Hour},
+			pc:   &pooledConn{createdAt: now.Add(-2 * time.Hour), idleSince: now},
+			want: true,
+		},
+		{
+			name: "exceeded IdleTimeout",
+			cfg:  PoolConfig{IdleTimeout: time.Minute},
+			pc:   &pooledConn{createdAt: now, idleSince: now.Add(-2 * time.Minute)},
+			want: true,
+		},
+		{
+			name: "unbounded config never expires",
+			cfg:  PoolConfig{},
+			pc:   &pooledConn{createdAt: now.Add(-24 * time.Hour), idleSince: now.Add(-24 * time.Hour)},
+			want: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewPool(c.cfg)
+			if got := p.expired(c.pc); got != c.want {
+				t.Errorf("expired() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWakeWaiterLockedWakesOldestOnly(t *testing.T) {
+	p := NewPool(PoolConfig{})
+	key := poolKey{host: "localhost", dbname: "cars", user: "admin"}
+
+	oldest := make(chan struct{})
+	newest := make(chan struct{})
+	p.waiters[key] = []chan struct{}{oldest, newest}
+
+	p.wakeWaiterLocked(key)
+
+	select {
+	case <-oldest:
+	default:
+		t.Error("wakeWaiterLocked did not wake the oldest waiter")
+	}
+
+	select {
+	case <-newest:
+		t.Error("wakeWaiterLocked woke more than the oldest waiter")
+	default:
+	}
+
+	if got := len(p.waiters[key]); got != 1 {
+		t.Errorf("len(waiters) = %d, want 1", got)
+	}
+}
+
+func TestWakeWaiterLockedNoopWhenNoWaiters(t *testing.T) {
+	p := NewPool(PoolConfig{})
+	key := poolKey{host: "localhost", dbname: "cars", user: "admin"}
+
+	// must not panic when there is nothing to wake
+	p.wakeWaiterLocked(key)
+}
+
+func TestRemoveWaiterLockedRemovesGivenWaiter(t *testing.T) {
+	p := NewPool(PoolConfig{})
+	key := poolKey{host: "localhost", dbname: "cars", user: "admin"}
+
+	first := make(chan struct{})
+	second := make(chan struct{})
+	p.waiters[key] = []chan struct{}{first, second}
+
+	p.removeWaiterLocked(key, first)
+
+	if got := len(p.waiters[key]); got != 1 {
+		t.Fatalf("len(waiters) = %d, want 1", got)
+	}
+	if p.waiters[key][0] != second {
+		t.Error("removeWaiterLocked removed the wrong waiter")
+	}
+}
+
+func TestRemoveWaiterLockedPassesOnAlreadyConsumedWakeup(t *testing.T) {
+	p := NewPool(PoolConfig{})
+	key := poolKey{host: "localhost", dbname: "cars", user: "admin"}
+
+	abandoned := make(chan struct{})
+	next := make(chan struct{})
+	p.waiters[key] = []chan struct{}{next}
+
+	// Simulate wakeWaiterLocked already having popped and closed abandoned
+	// (it raced ahead of this waiter's ctx.Done()) by removing it from the
+	// slice up front; removeWaiterLocked should then wake whoever is next.
+	p.removeWaiterLocked(key, abandoned)
+
+	select {
+	case <-next:
+	default:
+		t.Error("removeWaiterLocked did not pass the wakeup to the next waiter")
+	}
+}
@@ -0,0 +1,82 @@
+package obinary
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestStorageTypeFromURL(t *testing.T) {
+	cases := []struct {
+		url  string
+		want string
+	}{
+		{"plocal:/opt/orientdb/databases/cars", "plocal"},
+		{"remote:localhost/cars", "remote"},
+		{"nocolonhere", ""},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		if got := storageTypeFromURL(c.url); got != c.want {
+			t.Errorf("storageTypeFromURL(%q) = %q, want %q", c.url, got, c.want)
+		}
+	}
+}
+
+func TestBuildDatabaseInfoMap(t *testing.T) {
+	rawDbs := map[string]interface{}{
+		"cars":  "plocal:/opt/orientdb/databases/cars",
+		"users": "remote:localhost/users",
+	}
+
+	got, err := buildDatabaseInfoMap(rawDbs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]DatabaseInfo{
+		"cars":  {Name: "cars", Path: "plocal:/opt/orientdb/databases/cars", Type: "plocal"},
+		"users": {Name: "users", Path: "remote:localhost/users", Type: "remote"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildDatabaseInfoMap() = %#v, want %#v", got, want)
+	}
+}
+
+func TestBuildDatabaseInfoMapRejectsNonStringValue(t *testing.T) {
+	rawDbs := map[string]interface{}{
+		"cars": 42,
+	}
+
+	if _, err := buildDatabaseInfoMap(rawDbs); err == nil {
+		t.Error("expected an error for a non-string database URL, got nil")
+	}
+}
+
+func TestIsBrokenConnection(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"io.EOF", io.EOF, true},
+		{"wrapped io.EOF", fmt.Errorf("reading header: %w", io.EOF), true},
+		{"syscall.EPIPE", syscall.EPIPE, true},
+		{"wrapped syscall.EPIPE", fmt.Errorf("writing request: %w", syscall.EPIPE), true},
+		{"unrelated error", errors.New("boom"), false},
+		{"syscall.ECONNRESET", syscall.ECONNRESET, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isBrokenConnection(c.err); got != c.want {
+				t.Errorf("isBrokenConnection(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}